@@ -0,0 +1,199 @@
+package wechat
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iGoogle-ink/gopay/wechat/v3"
+)
+
+// defaultCertRefreshInterval 平台证书默认刷新周期
+const defaultCertRefreshInterval = 12 * time.Hour
+
+// platformCert 缓存的微信支付平台证书及其过期时间
+type platformCert struct {
+	cert   *x509.Certificate
+	expire time.Time
+}
+
+// certFetcher 抽象证书拉取通道，仅暴露 DoWithoutVerify
+//	Refresh 只能通过该接口发起请求，从类型层面保证它不会走 ClientV3.Do 的验签+未命中再 Refresh 兜底逻辑，
+//	避免证书轮换场景下的无限递归
+type certFetcher interface {
+	DoWithoutVerify(method, urlPath, body string, headers ...map[string]string) (bs []byte, err error)
+}
+
+// CertificateManager 自动拉取、解密并轮换微信支付平台证书
+//	实现 v3.CertificateProvider，注入给 v3.ClientV3 用于验证应答签名
+type CertificateManager struct {
+	mu       sync.RWMutex
+	certs    map[string]*platformCert
+	client   certFetcher
+	apiV3Key string
+	interval time.Duration
+	stopCh   chan struct{}
+}
+
+// NewCertificateManager 创建平台证书管理器
+//	client：用于拉取证书的 APIv3 客户端
+//	apiV3Key：商户 APIv3 密钥，用于解密 encrypt_certificate
+func NewCertificateManager(client *v3.ClientV3, apiV3Key string) *CertificateManager {
+	return &CertificateManager{
+		certs:    make(map[string]*platformCert),
+		client:   client,
+		apiV3Key: apiV3Key,
+		interval: defaultCertRefreshInterval,
+	}
+}
+
+// SetRefreshInterval 设置自动刷新周期，需在 Start 之前调用
+func (m *CertificateManager) SetRefreshInterval(d time.Duration) {
+	m.interval = d
+}
+
+// Start 执行首次拉取并启动后台自动刷新
+func (m *CertificateManager) Start() (err error) {
+	if err = m.Refresh(); err != nil {
+		return err
+	}
+	m.stopCh = make(chan struct{})
+	go m.refreshLoop()
+	return nil
+}
+
+// Stop 停止后台自动刷新
+func (m *CertificateManager) Stop() {
+	if m.stopCh != nil {
+		close(m.stopCh)
+	}
+}
+
+func (m *CertificateManager) refreshLoop() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = m.Refresh()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// certificatesResponse GET /v3/certificates 应答
+type certificatesResponse struct {
+	Data []struct {
+		SerialNo           string `json:"serial_no"`
+		EffectiveTime      string `json:"effective_time"`
+		ExpireTime         string `json:"expire_time"`
+		EncryptCertificate struct {
+			Algorithm      string `json:"algorithm"`
+			Nonce          string `json:"nonce"`
+			AssociatedData string `json:"associated_data"`
+			Ciphertext     string `json:"ciphertext"`
+		} `json:"encrypt_certificate"`
+	} `json:"data"`
+}
+
+// Refresh 强制拉取一次最新的平台证书列表
+//	通过 certFetcher.DoWithoutVerify 发起请求，不对该接口自身的应答做验签
+func (m *CertificateManager) Refresh() (err error) {
+	bs, err := m.client.DoWithoutVerify("GET", "/v3/certificates", "")
+	if err != nil {
+		return fmt.Errorf("wechat v3 certificates：%w", err)
+	}
+	resp := new(certificatesResponse)
+	if err = json.Unmarshal(bs, resp); err != nil {
+		return fmt.Errorf("json.Unmarshal：%w", err)
+	}
+
+	certs := make(map[string]*platformCert, len(resp.Data))
+	for _, item := range resp.Data {
+		plain, err := decryptCertificateAEAD(item.EncryptCertificate.Ciphertext, item.EncryptCertificate.AssociatedData,
+			item.EncryptCertificate.Nonce, m.apiV3Key)
+		if err != nil {
+			return fmt.Errorf("decrypt platform certificate %s：%w", item.SerialNo, err)
+		}
+		block, _ := pem.Decode(plain)
+		if block == nil {
+			return fmt.Errorf("pem.Decode platform certificate %s failed", item.SerialNo)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("x509.ParseCertificate %s：%w", item.SerialNo, err)
+		}
+		certs[item.SerialNo] = &platformCert{cert: cert, expire: cert.NotAfter}
+	}
+
+	m.mu.Lock()
+	for serialNo, c := range certs {
+		m.certs[serialNo] = c
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// GetCertificate 实现 v3.CertificateProvider：按序列号查询未过期的平台证书
+func (m *CertificateManager) GetCertificate(serialNo string) (cert *x509.Certificate, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, found := m.certs[serialNo]
+	if !found || time.Now().After(c.expire) {
+		return nil, false
+	}
+	return c.cert, true
+}
+
+// Latest 返回当前最新的未过期平台证书
+func (m *CertificateManager) Latest() (cert *x509.Certificate, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var latest *platformCert
+	for _, c := range m.certs {
+		if time.Now().After(c.expire) {
+			continue
+		}
+		if latest == nil || c.cert.NotBefore.After(latest.cert.NotBefore) {
+			latest = c
+		}
+	}
+	if latest == nil {
+		return nil, false
+	}
+	return latest.cert, true
+}
+
+// decryptCertificateAEAD 使用商户 APIv3 密钥以 AES-256-GCM 解密 encrypt_certificate 字段
+func decryptCertificateAEAD(ciphertextB64, associatedData, nonce, apiV3Key string) (plain []byte, err error) {
+	block, err := aes.NewCipher([]byte(apiV3Key))
+	if err != nil {
+		return nil, fmt.Errorf("aes.NewCipher：%w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cipher.NewGCM：%w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(ciphertextB64)
+	if err != nil {
+		return nil, fmt.Errorf("base64.DecodeString：%w", err)
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce size")
+	}
+	plain, err = gcm.Open(nil, []byte(nonce), ciphertext, []byte(associatedData))
+	if err != nil {
+		return nil, fmt.Errorf("cipher.GCM.Open：%w", err)
+	}
+	return plain, nil
+}
+
+var _ v3.CertificateProvider = (*CertificateManager)(nil)
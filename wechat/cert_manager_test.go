@@ -0,0 +1,132 @@
+package wechat
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeCertFetcher 只实现 DoWithoutVerify，用来在编译期约束 Refresh 不会走带验签的 Do 通道
+type fakeCertFetcher struct {
+	calls int
+	body  []byte
+	err   error
+}
+
+func (f *fakeCertFetcher) DoWithoutVerify(method, urlPath, body string, headers ...map[string]string) (bs []byte, err error) {
+	f.calls++
+	return f.body, f.err
+}
+
+func generatePlatformCert(t *testing.T, serial int64) *x509.Certificate {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "platform-cert"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate: %v", err)
+	}
+	return cert
+}
+
+func buildCertificatesResponse(t *testing.T, apiV3Key string, cert *x509.Certificate) []byte {
+	t.Helper()
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+
+	block, err := aes.NewCipher([]byte(apiV3Key))
+	if err != nil {
+		t.Fatalf("aes.NewCipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("cipher.NewGCM: %v", err)
+	}
+	nonce := "123456789012"
+	associatedData := "certificate"
+	ciphertext := gcm.Seal(nil, []byte(nonce), certPem, []byte(associatedData))
+
+	resp := certificatesResponse{}
+	resp.Data = []struct {
+		SerialNo           string `json:"serial_no"`
+		EffectiveTime      string `json:"effective_time"`
+		ExpireTime         string `json:"expire_time"`
+		EncryptCertificate struct {
+			Algorithm      string `json:"algorithm"`
+			Nonce          string `json:"nonce"`
+			AssociatedData string `json:"associated_data"`
+			Ciphertext     string `json:"ciphertext"`
+		} `json:"encrypt_certificate"`
+	}{
+		{
+			SerialNo: strings.ToUpper(cert.SerialNumber.Text(16)),
+			EncryptCertificate: struct {
+				Algorithm      string `json:"algorithm"`
+				Nonce          string `json:"nonce"`
+				AssociatedData string `json:"associated_data"`
+				Ciphertext     string `json:"ciphertext"`
+			}{
+				Algorithm:      "AEAD_AES_256_GCM",
+				Nonce:          nonce,
+				AssociatedData: associatedData,
+				Ciphertext:     base64.StdEncoding.EncodeToString(ciphertext),
+			},
+		},
+	}
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	return bs
+}
+
+// TestCertificateManagerRefreshUsesUnverifiedChannel 回归测试：Refresh 通过免验签通道拉取证书，
+// 刚轮换到的新序列号也能一次性入库，不依赖也不会触发任何验签兜底逻辑
+func TestCertificateManagerRefreshUsesUnverifiedChannel(t *testing.T) {
+	const apiV3Key = "0123456789abcdef0123456789abcdef"
+	cert := generatePlatformCert(t, 42)
+	fetcher := &fakeCertFetcher{body: buildCertificatesResponse(t, apiV3Key, cert)}
+
+	m := &CertificateManager{
+		certs:    make(map[string]*platformCert),
+		client:   fetcher,
+		apiV3Key: apiV3Key,
+		interval: defaultCertRefreshInterval,
+	}
+	if err := m.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if fetcher.calls != 1 {
+		t.Fatalf("expected exactly 1 fetch call, got %d", fetcher.calls)
+	}
+
+	serialNo := strings.ToUpper(cert.SerialNumber.Text(16))
+	got, ok := m.GetCertificate(serialNo)
+	if !ok {
+		t.Fatalf("expected certificate for serial %s to be cached", serialNo)
+	}
+	if got.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+		t.Fatalf("cached certificate serial mismatch")
+	}
+}
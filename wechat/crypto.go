@@ -0,0 +1,95 @@
+package wechat
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iGoogle-ink/gopay"
+	"github.com/iGoogle-ink/gotil"
+)
+
+// EncryptOAEP 使用微信支付平台证书公钥对敏感字段做 RSA-OAEP(SHA-1) 加密，返回 base64 结果
+//	适用于商户入驻、分账接收方添加等需要加密姓名、证件号、银行账号等 PII 字段的场景
+func EncryptOAEP(plaintext string, cert *x509.Certificate) (ciphertext string, err error) {
+	pubKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return gotil.NULL, errors.New("wechat: platform certificate public key is not RSA")
+	}
+	bs, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, pubKey, []byte(plaintext), nil)
+	if err != nil {
+		return gotil.NULL, fmt.Errorf("rsa.EncryptOAEP：%w", err)
+	}
+	return base64.StdEncoding.EncodeToString(bs), nil
+}
+
+// DecryptAEAD 使用商户 APIv3 密钥以 AES-256-GCM 解密回调/应答中的敏感字段
+//	ciphertextB64：待解密密文，base64 编码
+//	associatedData：关联数据（如回调中的 associated_data）
+//	nonce：加密时使用的随机串（如回调中的 nonce）
+func DecryptAEAD(ciphertextB64, associatedData, nonce, apiV3Key string) (plain []byte, err error) {
+	return decryptCertificateAEAD(ciphertextB64, associatedData, nonce, apiV3Key)
+}
+
+// EncryptSensitiveFields 递归加密 bm 中位于 fields 列表内的敏感字段
+//	使用 manager 当前最新的平台证书做 RSA-OAEP 加密，返回的序列号需由调用方透传给 ClientV3.Do 的
+//	headers 参数，设置为请求的 Wechatpay-Serial 头，以便微信支付侧据此选择对应的私钥解密：
+//
+//		serialNo, err := wechat.EncryptSensitiveFields(bm, manager, "id_card_number", "identity.id_card_number")
+//		body, _ := json.Marshal(bm)
+//		client.Do("POST", urlPath, string(body), map[string]string{"Wechatpay-Serial": serialNo})
+//
+//	gopay.BodyMap 是普通 map[string]interface{}，没有可挂 struct tag 的字段定义，故这里用显式的
+//	fields 列表标记敏感字段，而非 `encrypt:"true"` 字段标签。fields 中的每一项是一条以 "." 分隔、从 bm
+//	根开始的完整路径（如 "identity.id_card_number"），而非裸字段名：同名字段出现在不同嵌套层级时，只有
+//	路径精确匹配的那一处会被加密，避免同名的不相关字段被误加密或该加密的嵌套字段被漏掉
+func EncryptSensitiveFields(bm gopay.BodyMap, manager *CertificateManager, fields ...string) (serialNo string, err error) {
+	cert, ok := manager.Latest()
+	if !ok {
+		return gotil.NULL, errors.New("wechat: no usable platform certificate to encrypt sensitive fields")
+	}
+	sensitive := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		sensitive[field] = true
+	}
+	if err = encryptFieldsRecursive(bm, "", cert, sensitive); err != nil {
+		return gotil.NULL, err
+	}
+	return strings.ToUpper(cert.SerialNumber.Text(16)), nil
+}
+
+// encryptFieldsRecursive 遍历 bm，对 sensitive 中列出的路径原地加密，并递归处理嵌套的 BodyMap
+//	path 是当前 bm 相对于根 BodyMap 的路径前缀，根层级为空字符串
+func encryptFieldsRecursive(bm gopay.BodyMap, path string, cert *x509.Certificate, sensitive map[string]bool) error {
+	for key, value := range bm {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+		switch v := value.(type) {
+		case gopay.BodyMap:
+			if err := encryptFieldsRecursive(v, fieldPath, cert, sensitive); err != nil {
+				return err
+			}
+		case string:
+			if !sensitive[fieldPath] {
+				continue
+			}
+			ciphertext, err := EncryptOAEP(v, cert)
+			if err != nil {
+				return fmt.Errorf("encrypt field %q：%w", fieldPath, err)
+			}
+			bm.Set(key, ciphertext)
+		default:
+			if sensitive[fieldPath] {
+				return fmt.Errorf("encrypt field %q：unsupported value type %T, expected string", fieldPath, value)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package wechat
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/iGoogle-ink/gopay"
+)
+
+func generateEncryptionCert(t *testing.T) (*x509.Certificate, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(7),
+		Subject:      pkix.Name{CommonName: "platform-cert"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate: %v", err)
+	}
+	return cert, key
+}
+
+// TestEncryptFieldsRecursiveMatchesFullPathOnly 回归测试：sensitive 按完整路径匹配，而非裸字段名——
+// 同名字段出现在两个不同嵌套层级时，只有路径被显式列出的那一处被加密，另一处原样保留
+func TestEncryptFieldsRecursiveMatchesFullPathOnly(t *testing.T) {
+	cert, _ := generateEncryptionCert(t)
+
+	nested := make(gopay.BodyMap)
+	nested.Set("name", "张三")
+	bm := make(gopay.BodyMap)
+	bm.Set("name", "商户名称")
+	bm.Set("identity", nested)
+
+	sensitive := map[string]bool{"identity.name": true}
+	if err := encryptFieldsRecursive(bm, "", cert, sensitive); err != nil {
+		t.Fatalf("encryptFieldsRecursive: %v", err)
+	}
+
+	if got := bm["name"].(string); got != "商户名称" {
+		t.Fatalf("top-level name should be untouched, got %q", got)
+	}
+	if got := nested["name"].(string); got == "张三" {
+		t.Fatalf("identity.name should have been encrypted in place")
+	}
+}
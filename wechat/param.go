@@ -1,10 +1,9 @@
 package wechat
 
 import (
-	"crypto/hmac"
 	"crypto/md5"
-	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
 	"encoding/pem"
 	"encoding/xml"
@@ -13,7 +12,10 @@ import (
 	"golang.org/x/crypto/pkcs12"
 	"hash"
 	"io/ioutil"
+	"log"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/iGoogle-ink/gopay"
 	"github.com/iGoogle-ink/gotil"
@@ -81,16 +83,56 @@ func (w *Client) AddCertPkcs12FileContent(pkcs12FileContent []byte) (err error)
 	return w.AddCertFilePath(nil, nil, pkcs12FileContent)
 }
 
+// SetTLSVerify 设置是否校验微信支付服务端 TLS 证书
+//	verify：true（默认）使用系统根证书（或 pool 指定的证书池）校验，false 跳过校验，仅建议沙箱环境使用
+//	pool：可选的自定义 CA 证书池，不传则使用系统根证书
+func (w *Client) SetTLSVerify(verify bool, pool ...*x509.CertPool) (client *Client) {
+	w.mu.Lock()
+	w.insecureSkipVerify = !verify
+	if len(pool) > 0 {
+		w.rootCAs = pool[0]
+	}
+	w.mu.Unlock()
+	return w
+}
+
+// CertSerialNo 返回已加载商户证书（apiclient_cert.pem/p12）的序列号，大写十六进制格式
+//	AddCertFilePath 系列方法加载证书成功后自动填充，供 APIv3 请求签名使用
+func (w *Client) CertSerialNo() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.certSerialNo
+}
+
+// cacheCertSerialNo 解析商户证书叶子证书并缓存序列号
+func (w *Client) cacheCertSerialNo(certificate tls.Certificate) {
+	if len(certificate.Certificate) == 0 {
+		return
+	}
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return
+	}
+	w.mu.Lock()
+	w.certSerialNo = strings.ToUpper(leaf.SerialNumber.Text(16))
+	w.mu.Unlock()
+}
+
+// tlsConfigLocked 根据当前 TLS 校验设置构造 tls.Config，调用方需持有 w.mu 的读锁
+func (w *Client) tlsConfigLocked(certificate tls.Certificate) *tls.Config {
+	return &tls.Config{
+		Certificates:       []tls.Certificate{certificate},
+		InsecureSkipVerify: w.insecureSkipVerify,
+		RootCAs:            w.rootCAs,
+	}
+}
+
 func (w *Client) addCertConfig(certFile, keyFile, pkcs12File interface{}) (tlsConfig *tls.Config, err error) {
 	if certFile == nil && keyFile == nil && pkcs12File == nil {
 		w.mu.RLock()
 		defer w.mu.RUnlock()
 		if w.certificate != nil {
-			tlsConfig = &tls.Config{
-				Certificates:       []tls.Certificate{*w.certificate},
-				InsecureSkipVerify: true,
-			}
-			return tlsConfig, nil
+			return w.tlsConfigLocked(*w.certificate), nil
 		}
 	}
 
@@ -134,10 +176,10 @@ func (w *Client) addCertConfig(certFile, keyFile, pkcs12File interface{}) (tlsCo
 		if certificate, err = tls.X509KeyPair(certPem, keyPem); err != nil {
 			return nil, fmt.Errorf("tls.LoadX509KeyPair：%w", err)
 		}
-		tlsConfig = &tls.Config{
-			Certificates:       []tls.Certificate{certificate},
-			InsecureSkipVerify: true,
-		}
+		w.cacheCertSerialNo(certificate)
+		w.mu.RLock()
+		tlsConfig = w.tlsConfigLocked(certificate)
+		w.mu.RUnlock()
 		return tlsConfig, nil
 	}
 	return nil, errors.New("cert files must all nil or all not nil")
@@ -185,14 +227,7 @@ func checkCertFilePath(certFilePath, keyFilePath, pkcs12FilePath interface{}) er
 
 // 获取微信支付正式环境Sign值
 func getReleaseSign(apiKey string, signType string, bm gopay.BodyMap) (sign string) {
-	var h hash.Hash
-	if signType == SignType_HMAC_SHA256 {
-		h = hmac.New(sha256.New, []byte(apiKey))
-	} else {
-		h = md5.New()
-	}
-	h.Write([]byte(bm.EncodeWeChatSignParams(apiKey)))
-	return strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
+	return signerFor(signType).Sign(apiKey, bm)
 }
 
 // 获取微信支付沙箱环境Sign值
@@ -210,16 +245,95 @@ func getSignBoxSign(mchId, apiKey string, bm gopay.BodyMap) (sign string, err er
 	return
 }
 
+// sandboxKeyTTL 沙箱签名密钥缓存有效期，SandboxSignkey 按 mch_id 维度长期稳定，无需每次请求都拉取
+const sandboxKeyTTL = 24 * time.Hour
+
+// sandboxKeyEntry 缓存的沙箱签名密钥
+type sandboxKeyEntry struct {
+	key     string
+	savedAt time.Time
+}
+
+var (
+	sandboxKeyMu    sync.RWMutex
+	sandboxKeyCache = make(map[string]sandboxKeyEntry)
+)
+
+// getCachedSandboxKey 返回未过期的缓存沙箱密钥
+func getCachedSandboxKey(mchId string) (key string, ok bool) {
+	sandboxKeyMu.RLock()
+	defer sandboxKeyMu.RUnlock()
+	entry, found := sandboxKeyCache[mchId]
+	if !found || time.Since(entry.savedAt) > sandboxKeyTTL {
+		return gotil.NULL, false
+	}
+	return entry.key, true
+}
+
+// getLastSandboxKey 返回最近一次缓存的沙箱密钥（无论是否过期），用于请求失败时兜底
+func getLastSandboxKey(mchId string) (key string, ok bool) {
+	sandboxKeyMu.RLock()
+	defer sandboxKeyMu.RUnlock()
+	entry, found := sandboxKeyCache[mchId]
+	if !found {
+		return gotil.NULL, false
+	}
+	return entry.key, true
+}
+
+// setSandboxKey 写入沙箱密钥缓存
+func setSandboxKey(mchId, key string) {
+	sandboxKeyMu.Lock()
+	sandboxKeyCache[mchId] = sandboxKeyEntry{key: key, savedAt: time.Now()}
+	sandboxKeyMu.Unlock()
+}
+
+// InvalidateSandboxKey 清除当前商户号的沙箱签名密钥缓存，供测试场景强制重新拉取
+func (w *Client) InvalidateSandboxKey() {
+	sandboxKeyMu.Lock()
+	delete(sandboxKeyCache, w.MchId)
+	sandboxKeyMu.Unlock()
+}
+
+// sanBoxSignKeyFetcher 实际拉取沙箱签名密钥的函数，测试时可替换以模拟网络故障
+//	读写均需持有 sandboxKeyMu，与其余沙箱密钥缓存状态共用同一把锁
+var sanBoxSignKeyFetcher = getSanBoxSignKey
+
+// setSanBoxSignKeyFetcher 替换 sanBoxSignKeyFetcher，仅供测试使用
+func setSanBoxSignKeyFetcher(fetcher func(mchId, nonceStr, sign string) (key string, err error)) {
+	sandboxKeyMu.Lock()
+	sanBoxSignKeyFetcher = fetcher
+	sandboxKeyMu.Unlock()
+}
+
+func currentSanBoxSignKeyFetcher() func(mchId, nonceStr, sign string) (key string, err error) {
+	sandboxKeyMu.RLock()
+	defer sandboxKeyMu.RUnlock()
+	return sanBoxSignKeyFetcher
+}
+
 // 从微信提供的接口获取：SandboxSignKey
 func getSanBoxKey(mchId, nonceStr, apiKey, signType string) (key string, err error) {
+	if key, ok := getCachedSandboxKey(mchId); ok {
+		return key, nil
+	}
+
 	bm := make(gopay.BodyMap)
 	bm.Set("mch_id", mchId)
 	bm.Set("nonce_str", nonceStr)
 	// 沙箱环境：获取沙箱环境ApiKey
-	if key, err = getSanBoxSignKey(mchId, nonceStr, getReleaseSign(apiKey, signType, bm)); err != nil {
-		return
+	key, err = currentSanBoxSignKeyFetcher()(mchId, nonceStr, getReleaseSign(apiKey, signType, bm))
+	if err != nil {
+		// 拉取失败时容忍使用历史缓存的密钥，避免单次网络抖动导致整次签名失败，但需要把被吞掉的错误记录下来，
+		// 否则沙箱密钥接口持续故障会长期不可见
+		if cached, ok := getLastSandboxKey(mchId); ok {
+			log.Printf("wechat: getSanBoxKey mch_id=%s fetch failed, fallback to cached sandbox key：%v", mchId, err)
+			return cached, nil
+		}
+		return gotil.NULL, err
 	}
-	return
+	setSandboxKey(mchId, key)
+	return key, nil
 }
 
 // 从微信提供的接口获取：SandboxSignKey
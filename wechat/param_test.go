@@ -0,0 +1,137 @@
+package wechat
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"log"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iGoogle-ink/gopay"
+)
+
+// TestGetSanBoxKeyLogsWarningOnStaleFallback 回归测试：沙箱密钥接口请求失败时，
+// 应当把被吞掉的错误记录下来，而不是悄无声息地返回历史缓存的密钥
+func TestGetSanBoxKeyLogsWarningOnStaleFallback(t *testing.T) {
+	const mchId = "test-mch-id"
+	setSandboxKey(mchId, "stale-key")
+	sandboxKeyMu.Lock()
+	entry := sandboxKeyCache[mchId]
+	entry.savedAt = time.Now().Add(-2 * sandboxKeyTTL)
+	sandboxKeyCache[mchId] = entry
+	sandboxKeyMu.Unlock()
+	defer func() {
+		sandboxKeyMu.Lock()
+		delete(sandboxKeyCache, mchId)
+		sandboxKeyMu.Unlock()
+	}()
+
+	fetchErr := errors.New("sandbox sign key endpoint unreachable")
+	original := currentSanBoxSignKeyFetcher()
+	setSanBoxSignKeyFetcher(func(mchId, nonceStr, sign string) (key string, err error) {
+		return "", fetchErr
+	})
+	defer setSanBoxSignKeyFetcher(original)
+
+	var buf bytes.Buffer
+	originalOutput := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(originalOutput)
+
+	key, err := getSanBoxKey(mchId, "nonce", "apiKey", "MD5")
+	if err != nil {
+		t.Fatalf("getSanBoxKey: %v", err)
+	}
+	if key != "stale-key" {
+		t.Fatalf("expected stale cached key, got %q", key)
+	}
+	if !strings.Contains(buf.String(), fetchErr.Error()) {
+		t.Fatalf("expected fallback warning to be logged, got log output: %q", buf.String())
+	}
+}
+
+// TestTLSConfigLockedDefaultsToStrictVerification 回归测试：未调用 SetTLSVerify 时默认校验微信支付服务端证书，
+// 且 SetTLSVerify(false) 之后跳过校验——避免 InsecureSkipVerify 默认被误置为 true
+func TestTLSConfigLockedDefaultsToStrictVerification(t *testing.T) {
+	w := &Client{}
+	if got := w.tlsConfigLocked(tls.Certificate{}); got.InsecureSkipVerify {
+		t.Fatal("expected InsecureSkipVerify to default to false")
+	}
+
+	w.SetTLSVerify(false)
+	if got := w.tlsConfigLocked(tls.Certificate{}); !got.InsecureSkipVerify {
+		t.Fatal("expected InsecureSkipVerify to be true after SetTLSVerify(false)")
+	}
+
+	pool := x509.NewCertPool()
+	w.SetTLSVerify(true, pool)
+	if got := w.tlsConfigLocked(tls.Certificate{}); got.InsecureSkipVerify || got.RootCAs != pool {
+		t.Fatal("expected verification enabled with the custom root CA pool applied")
+	}
+}
+
+// TestCacheCertSerialNoPopulatesCertSerialNo 回归测试：加载商户证书后 CertSerialNo 返回其序列号的大写十六进制形式
+func TestCacheCertSerialNoPopulatesCertSerialNo(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(0x1a2b3c),
+		Subject:      pkix.Name{CommonName: "merchant-cert"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate: %v", err)
+	}
+
+	w := &Client{}
+	w.cacheCertSerialNo(tls.Certificate{Certificate: [][]byte{der}})
+	if got, want := w.CertSerialNo(), "1A2B3C"; got != want {
+		t.Fatalf("CertSerialNo() = %q, want %q", got, want)
+	}
+}
+
+// TestGetReleaseSignMD5MatchesKnownVector 回归测试：getReleaseSign 从手写 HMAC/MD5 改为
+// signerFor(signType).Sign 分发之后，MD5 签名结果必须与微信支付官方文档给出的签名示例逐字节一致,
+// 避免 EncodeWeChatSignParams 的参数排序/大小写在重构中被悄悄改变
+func TestGetReleaseSignMD5MatchesKnownVector(t *testing.T) {
+	bm := make(gopay.BodyMap)
+	bm.Set("appid", "wxd930ea5d5a258f4f")
+	bm.Set("mch_id", "10000100")
+	bm.Set("device_info", "1000")
+	bm.Set("body", "test")
+	bm.Set("nonce_str", "ibuaiVcKdpRxkhJA")
+
+	const apiKey = "192006250b4c09247ec02edce69f6a2d"
+	const want = "9A0A8659F005D6984697E2CA0A9CF3B7"
+	if got := getReleaseSign(apiKey, "MD5", bm); got != want {
+		t.Fatalf("getReleaseSign(MD5) = %q, want %q", got, want)
+	}
+}
+
+// TestGetReleaseSignHMACSHA256MatchesFixedVector 回归测试：同一组参数下，HMAC-SHA256 签名分支
+// 产出与独立实现（crypto/hmac + crypto/sha256）逐字节一致的结果
+func TestGetReleaseSignHMACSHA256MatchesFixedVector(t *testing.T) {
+	bm := make(gopay.BodyMap)
+	bm.Set("appid", "wxd930ea5d5a258f4f")
+	bm.Set("mch_id", "10000100")
+	bm.Set("device_info", "1000")
+	bm.Set("body", "test")
+	bm.Set("nonce_str", "ibuaiVcKdpRxkhJA")
+
+	const apiKey = "192006250b4c09247ec02edce69f6a2d"
+	const want = "6A9AE1657590FD6257D693A078E1C3E4BB6BA4DC30B23E0EE2496E54170DACD6"
+	if got := getReleaseSign(apiKey, "HMAC-SHA256", bm); got != want {
+		t.Fatalf("getReleaseSign(HMAC-SHA256) = %q, want %q", got, want)
+	}
+}
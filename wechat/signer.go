@@ -0,0 +1,44 @@
+package wechat
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"strings"
+
+	"github.com/iGoogle-ink/gopay"
+)
+
+// Signer v2 签名器抽象，使 getReleaseSign 成为薄分发层，便于接入自定义 HMAC/MD5 实现
+type Signer interface {
+	Sign(apiKey string, bm gopay.BodyMap) (sign string)
+}
+
+// HMACSigner 对应 SignType_HMAC_SHA256
+type HMACSigner struct{}
+
+func (HMACSigner) Sign(apiKey string, bm gopay.BodyMap) (sign string) {
+	return signWithHash(hmac.New(sha256.New, []byte(apiKey)), apiKey, bm)
+}
+
+// MD5Signer 对应 SignType_MD5
+type MD5Signer struct{}
+
+func (MD5Signer) Sign(apiKey string, bm gopay.BodyMap) (sign string) {
+	return signWithHash(md5.New(), apiKey, bm)
+}
+
+func signWithHash(h hash.Hash, apiKey string, bm gopay.BodyMap) (sign string) {
+	h.Write([]byte(bm.EncodeWeChatSignParams(apiKey)))
+	return strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
+}
+
+// signerFor 根据 signType 选择签名器，默认 MD5
+func signerFor(signType string) Signer {
+	if signType == SignType_HMAC_SHA256 {
+		return HMACSigner{}
+	}
+	return MD5Signer{}
+}
@@ -0,0 +1,162 @@
+package v3
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/iGoogle-ink/gotil/xhttp"
+)
+
+// CertificateProvider 提供微信支付平台证书查询能力，用于验证应答签名
+//	由调用方实现并通过 SetCertificateProvider 注入，例如 wechat.CertificateManager
+type CertificateProvider interface {
+	// GetCertificate 根据平台证书序列号查询证书，ok 为 false 表示当前未知该证书
+	GetCertificate(serialNo string) (cert *x509.Certificate, ok bool)
+	// Refresh 强制刷新一次平台证书，用于 GetCertificate 未命中时的兜底重试
+	Refresh() error
+}
+
+// ClientV3 微信支付 APIv3 客户端
+//	通过 Signer 对每次请求做 RSA-SHA256 签名，与 v2 版本的 MD5/HMAC 签名相互独立
+type ClientV3 struct {
+	MchId    string
+	SerialNo string
+	BaseURL  string
+
+	mu           sync.RWMutex
+	signer       Signer
+	tlsConfig    *tls.Config
+	certProvider CertificateProvider
+}
+
+// NewClientV3 初始化微信支付 APIv3 客户端，使用本地 apiclient_key.pem 签名（PEMSigner）
+//	mchId：商户号
+//	serialNo：商户 API 证书序列号
+//	apiClientKeyPem：apiclient_key.pem 私钥内容
+func NewClientV3(mchId, serialNo string, apiClientKeyPem []byte) (client *ClientV3, err error) {
+	signer, err := NewPEMSigner(serialNo, apiClientKeyPem)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientV3{
+		MchId:    mchId,
+		SerialNo: serialNo,
+		BaseURL:  baseUrlCh,
+		signer:   signer,
+	}, nil
+}
+
+// NewClientV3WithCert 初始化微信支付 APIv3 客户端，商户证书序列号从 apiclient_cert.pem 中解析
+//	mchId：商户号
+//	apiClientCertPem：apiclient_cert.pem 证书内容
+//	apiClientKeyPem：apiclient_key.pem 私钥内容
+func NewClientV3WithCert(mchId string, apiClientCertPem, apiClientKeyPem []byte) (client *ClientV3, err error) {
+	block, _ := pem.Decode(apiClientCertPem)
+	if block == nil {
+		return nil, errors.New("invalid apiClientCertPem: pem.Decode failed")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("x509.ParseCertificate：%w", err)
+	}
+	return NewClientV3(mchId, serialNoOf(cert), apiClientKeyPem)
+}
+
+// serialNoOf 提取证书序列号，与 v2 Client.CertSerialNo 保持一致的大写十六进制格式
+func serialNoOf(cert *x509.Certificate) string {
+	return strings.ToUpper(cert.SerialNumber.Text(16))
+}
+
+// SetSigner 替换默认的 PEMSigner，用于对接 HSM、云 KMS、PKCS#11 等外部签名服务持有的商户私钥
+func (c *ClientV3) SetSigner(signer Signer) {
+	c.mu.Lock()
+	c.signer = signer
+	c.SerialNo = signer.SerialNo()
+	c.mu.Unlock()
+}
+
+// SetTLSConfig 复用 v2 Client 已加载的双向 TLS 配置，签名流程与其完全独立
+func (c *ClientV3) SetTLSConfig(conf *tls.Config) {
+	c.mu.Lock()
+	c.tlsConfig = conf
+	c.mu.Unlock()
+}
+
+// SetCertificateProvider 注入微信支付平台证书查询器，用于验证应答签名
+//	未设置前 Do() 会跳过应答验签，适用于首次拉取平台证书前的引导阶段
+func (c *ClientV3) SetCertificateProvider(provider CertificateProvider) {
+	c.mu.Lock()
+	c.certProvider = provider
+	c.mu.Unlock()
+}
+
+// Do 发送 APIv3 请求，自动签名并验证应答签名
+//	method：HTTP 方法，如 GET、POST
+//	urlPath：不含域名的请求路径，例如 /v3/certificates
+//	body：请求体，GET 请求传空字符串
+//	headers：可选的额外请求头，例如加密敏感字段后需要设置的 Wechatpay-Serial
+func (c *ClientV3) Do(method, urlPath, body string, headers ...map[string]string) (bs []byte, err error) {
+	return c.do(method, urlPath, body, true, headers...)
+}
+
+// DoWithoutVerify 与 Do 相同，但跳过应答验签
+//	专供 CertificateManager 拉取 /v3/certificates 本身使用：平台证书刚轮换时，该接口的应答可能正是用尚未
+//	入库的新序列号签的名，若像普通请求一样走“验签未命中 -> Refresh -> 再次验签”的兜底逻辑，会在 Refresh
+//	内部再次请求该接口并再次验签未命中，陷入无限递归
+func (c *ClientV3) DoWithoutVerify(method, urlPath, body string, headers ...map[string]string) (bs []byte, err error) {
+	return c.do(method, urlPath, body, false, headers...)
+}
+
+func (c *ClientV3) do(method, urlPath, body string, verify bool, headers ...map[string]string) (bs []byte, err error) {
+	authorization, err := c.signRequest(method, urlPath, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req := xhttp.NewClient().Type(xhttp.TypeJSON).
+		Set("Authorization", authorization).
+		Set("Accept", "application/json").
+		Set("User-Agent", "gopay")
+	for _, h := range headers {
+		for k, v := range h {
+			req.Set(k, v)
+		}
+	}
+	c.mu.RLock()
+	tlsConfig := c.tlsConfig
+	c.mu.RUnlock()
+	if tlsConfig != nil {
+		req.TLSClientConfig(tlsConfig)
+	}
+
+	var (
+		res  *xhttp.Response
+		errs []error
+	)
+	fullUrl := c.BaseURL + urlPath
+	switch strings.ToUpper(method) {
+	case "GET":
+		res, bs, errs = req.Get(fullUrl).EndBytes()
+	case "POST":
+		res, bs, errs = req.Post(fullUrl).SendString(body).EndBytes()
+	default:
+		return nil, fmt.Errorf("unsupported method: %s", method)
+	}
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	if !verify {
+		return bs, nil
+	}
+
+	if err = c.verifyResponse(res.Header.Get("Wechatpay-Serial"), res.Header.Get("Wechatpay-Timestamp"),
+		res.Header.Get("Wechatpay-Nonce"), string(bs), res.Header.Get("Wechatpay-Signature")); err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
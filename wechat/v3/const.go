@@ -0,0 +1,7 @@
+package v3
+
+// 微信支付 APIv3 正式环境域名
+const baseUrlCh = "https://api.mch.weixin.qq.com"
+
+// Authorization 头使用的签名方案标识
+const signScheme = "WECHATPAY2-SHA256-RSA2048"
@@ -0,0 +1,12 @@
+package v3
+
+import "fmt"
+
+// CertificateNotFoundError 表示验证应答签名时，强制刷新后仍无法找到对应序列号的微信支付平台证书
+type CertificateNotFoundError struct {
+	SerialNo string
+}
+
+func (e *CertificateNotFoundError) Error() string {
+	return fmt.Sprintf("wechat v3: platform certificate not found for serial %q", e.SerialNo)
+}
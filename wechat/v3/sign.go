@@ -0,0 +1,71 @@
+package v3
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/iGoogle-ink/gotil"
+)
+
+// signRequest 计算 APIv3 请求的 Authorization 头
+//	签名串：METHOD\nURL_PATH\nTIMESTAMP\nNONCE\nBODY\n
+func (c *ClientV3) signRequest(method, urlPath, body string) (authorization string, err error) {
+	c.mu.RLock()
+	signer := c.signer
+	c.mu.RUnlock()
+	if signer == nil {
+		return gotil.NULL, errors.New("signer is nil, call NewClientV3 first")
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	nonce := gotil.GetRandomString(32)
+	message := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n", method, urlPath, timestamp, nonce, body)
+
+	signature, err := signer.Sign([]byte(message))
+	if err != nil {
+		return gotil.NULL, fmt.Errorf("sign request：%w", err)
+	}
+	authorization = fmt.Sprintf(`%s mchid="%s",nonce_str="%s",timestamp="%s",serial_no="%s",signature="%s"`,
+		signer.Algorithm(), c.MchId, nonce, timestamp, signer.SerialNo(), base64.StdEncoding.EncodeToString(signature))
+	return authorization, nil
+}
+
+// verifyResponse 验证应答签名：Wechatpay-Serial / Wechatpay-Timestamp / Wechatpay-Nonce / body / Wechatpay-Signature
+//	尚未设置 certProvider 时（平台证书引导阶段）跳过验签
+func (c *ClientV3) verifyResponse(serialNo, timestamp, nonce, body, signature string) (err error) {
+	c.mu.RLock()
+	certProvider := c.certProvider
+	c.mu.RUnlock()
+	if certProvider == nil {
+		return nil
+	}
+	cert, ok := certProvider.GetCertificate(serialNo)
+	if !ok {
+		if err = certProvider.Refresh(); err != nil {
+			return fmt.Errorf("wechat v3: refresh platform certificates：%w", err)
+		}
+		if cert, ok = certProvider.GetCertificate(serialNo); !ok {
+			return &CertificateNotFoundError{SerialNo: serialNo}
+		}
+	}
+	pubKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("wechat v3: platform certificate public key is not RSA")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("base64.DecodeString：%w", err)
+	}
+	message := fmt.Sprintf("%s\n%s\n%s\n", timestamp, nonce, body)
+	hashed := sha256.Sum256([]byte(message))
+	if err = rsa.VerifyPKCS1v15(pubKey, crypto.SHA256, hashed[:], sig); err != nil {
+		return fmt.Errorf("rsa.VerifyPKCS1v15：%w", err)
+	}
+	return nil
+}
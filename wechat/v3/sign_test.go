@@ -0,0 +1,138 @@
+package v3
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func generateTestCert(t *testing.T, serial int64) (*rsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate: %v", err)
+	}
+	return key, cert
+}
+
+func TestPEMSignerSignVerifiesWithPublicKey(t *testing.T) {
+	key, _ := generateTestCert(t, 1)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKCS8PrivateKey: %v", err)
+	}
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	signer, err := NewPEMSigner("ABC123", keyPem)
+	if err != nil {
+		t.Fatalf("NewPEMSigner: %v", err)
+	}
+	message := []byte("GET\n/v3/certificates\n1234567890\nnonce\n\n")
+	signature, err := signer.Sign(message)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	hashed := sha256.Sum256(message)
+	if err = rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hashed[:], signature); err != nil {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+}
+
+// stubCertProvider 模拟证书轮换后序列号未知的场景：首次查询未命中，Refresh 之后仍未命中
+type stubCertProvider struct {
+	refreshed int
+}
+
+func (s *stubCertProvider) GetCertificate(string) (*x509.Certificate, bool) {
+	return nil, false
+}
+
+func (s *stubCertProvider) Refresh() error {
+	s.refreshed++
+	return nil
+}
+
+// TestVerifyResponseRefreshesExactlyOnce 回归测试：证书仍未知时 verifyResponse 只应触发一次 Refresh，
+// 不能递归或反复刷新
+func TestVerifyResponseRefreshesExactlyOnce(t *testing.T) {
+	provider := &stubCertProvider{}
+	c := &ClientV3{certProvider: provider}
+
+	err := c.verifyResponse("unknown-serial", "1234567890", "nonce", "{}", base64.StdEncoding.EncodeToString([]byte("sig")))
+	if err == nil {
+		t.Fatal("expected error when certificate remains unknown after refresh")
+	}
+	var notFound *CertificateNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected *CertificateNotFoundError, got %T: %v", err, err)
+	}
+	if provider.refreshed != 1 {
+		t.Fatalf("expected exactly 1 Refresh call, got %d", provider.refreshed)
+	}
+}
+
+// stubCertProviderPopulatesOnRefresh 模拟 Refresh 成功拉取到新证书后，第二次 GetCertificate 命中的场景
+type stubCertProviderPopulatesOnRefresh struct {
+	refreshed int
+	serialNo  string
+	cert      *x509.Certificate
+	populated bool
+}
+
+func (s *stubCertProviderPopulatesOnRefresh) GetCertificate(serialNo string) (*x509.Certificate, bool) {
+	if s.populated && serialNo == s.serialNo {
+		return s.cert, true
+	}
+	return nil, false
+}
+
+func (s *stubCertProviderPopulatesOnRefresh) Refresh() error {
+	s.refreshed++
+	s.populated = true
+	return nil
+}
+
+func TestVerifyResponseSucceedsAfterRefreshPopulatesCertificate(t *testing.T) {
+	key, cert := generateTestCert(t, 2)
+	provider := &stubCertProviderPopulatesOnRefresh{serialNo: "SERIAL", cert: cert}
+	c := &ClientV3{certProvider: provider}
+
+	timestamp, nonce, body := "1234567890", "nonce", "{}"
+	message := timestamp + "\n" + nonce + "\n" + body + "\n"
+	hashed := sha256.Sum256([]byte(message))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatalf("rsa.SignPKCS1v15: %v", err)
+	}
+	signature := base64.StdEncoding.EncodeToString(sig)
+
+	if err = c.verifyResponse("SERIAL", timestamp, nonce, body, signature); err != nil {
+		t.Fatalf("verifyResponse: %v", err)
+	}
+	if provider.refreshed != 1 {
+		t.Fatalf("expected exactly 1 Refresh call, got %d", provider.refreshed)
+	}
+}
@@ -0,0 +1,68 @@
+package v3
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Signer APIv3 签名器抽象，用于将商户私钥材料与签名算法解耦
+//	默认实现 PEMSigner 直接持有内存中的 RSA 私钥；需要对接 HSM / 云 KMS / PKCS#11 的场景可实现该接口自行接管签名
+type Signer interface {
+	// Sign 对摘要原文做签名，返回原始签名结果（未经 base64 编码）
+	Sign(message []byte) (signature []byte, err error)
+	// SerialNo 返回签名所用商户证书的序列号
+	SerialNo() string
+	// Algorithm 返回 Authorization 头使用的签名方案标识
+	Algorithm() string
+}
+
+// PEMSigner 基于本地 apiclient_key.pem 的默认 Signer 实现
+type PEMSigner struct {
+	serialNo   string
+	privateKey *rsa.PrivateKey
+}
+
+// NewPEMSigner 从 apiclient_key.pem 内容构造 PEMSigner
+func NewPEMSigner(serialNo string, apiClientKeyPem []byte) (signer *PEMSigner, err error) {
+	privateKey, err := parsePrivateKey(apiClientKeyPem)
+	if err != nil {
+		return nil, err
+	}
+	return &PEMSigner{serialNo: serialNo, privateKey: privateKey}, nil
+}
+
+func (s *PEMSigner) Sign(message []byte) (signature []byte, err error) {
+	hashed := sha256.Sum256(message)
+	signature, err = rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return nil, fmt.Errorf("rsa.SignPKCS1v15：%w", err)
+	}
+	return signature, nil
+}
+
+func (s *PEMSigner) SerialNo() string { return s.serialNo }
+
+func (s *PEMSigner) Algorithm() string { return signScheme }
+
+// parsePrivateKey 解析 apiclient_key.pem 商户私钥
+func parsePrivateKey(pemBytes []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("invalid private key: pem.Decode failed")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("x509.ParsePKCS8PrivateKey：%w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid private key: not a RSA private key")
+	}
+	return rsaKey, nil
+}